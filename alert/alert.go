@@ -0,0 +1,23 @@
+// Package alert turns raw threshold breaches into notifications fanned
+// out to one or more sinks (stdout, webhook, email), with per-alert-key
+// cooldown so the same condition doesn't re-fire every poll interval.
+package alert
+
+import (
+	"context"
+	"time"
+)
+
+// Alert describes a single threshold breach, or its resolution.
+type Alert struct {
+	Target   string    `json:"target"`
+	Key      string    `json:"key"`
+	Message  string    `json:"message"`
+	Resolved bool      `json:"resolved"`
+	Time     time.Time `json:"time"`
+}
+
+// Sink delivers an Alert somewhere: stdout, a webhook, an inbox, ...
+type Sink interface {
+	Notify(ctx context.Context, a Alert) error
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// state tracks an alert key that is currently firing.
+type state struct {
+	lastNotify time.Time
+}
+
+// Router fans an alert condition out to every configured Sink, applying
+// a per-key cooldown so a condition that stays true doesn't renotify on
+// every poll, and emitting a resolved notification once the condition
+// clears.
+type Router struct {
+	sinks    []Sink
+	cooldown time.Duration
+
+	mu     sync.Mutex
+	active map[string]*state
+}
+
+// NewRouter builds a Router that notifies sinks, re-firing an active
+// alert at most once per cooldown.
+func NewRouter(cooldown time.Duration, sinks ...Sink) *Router {
+	return &Router{
+		sinks:    sinks,
+		cooldown: cooldown,
+		active:   make(map[string]*state),
+	}
+}
+
+// Fire reports the current state of the condition identified by
+// target+key. firing=true notifies sinks immediately the first time the
+// key starts firing, then at most once per cooldown while it stays
+// true. firing=false, for a key that was previously firing, sends a
+// single resolved notification and clears the key.
+func (r *Router) Fire(ctx context.Context, target, key, message string, firing bool) {
+	id := target + "/" + key
+	now := time.Now()
+
+	r.mu.Lock()
+	st, wasFiring := r.active[id]
+
+	switch {
+	case firing && !wasFiring:
+		r.active[id] = &state{lastNotify: now}
+		r.mu.Unlock()
+		r.notifyAll(ctx, Alert{Target: target, Key: key, Message: message, Time: now})
+
+	case firing && wasFiring:
+		renotify := now.Sub(st.lastNotify) >= r.cooldown
+		if renotify {
+			st.lastNotify = now
+		}
+		r.mu.Unlock()
+		if renotify {
+			r.notifyAll(ctx, Alert{Target: target, Key: key, Message: message, Time: now})
+		}
+
+	case !firing && wasFiring:
+		delete(r.active, id)
+		r.mu.Unlock()
+		r.notifyAll(ctx, Alert{Target: target, Key: key, Message: message, Resolved: true, Time: now})
+
+	default: // !firing && !wasFiring
+		r.mu.Unlock()
+	}
+}
+
+func (r *Router) notifyAll(ctx context.Context, a Alert) {
+	for _, sink := range r.sinks {
+		if err := sink.Notify(ctx, a); err != nil {
+			log.Printf("alert: не удалось доставить уведомление через %T: %v", sink, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// StdoutSink prints alerts the same way the original checkMetrics did,
+// prefixed with the target name and a RESOLVED marker on clear.
+type StdoutSink struct{}
+
+func (StdoutSink) Notify(_ context.Context, a Alert) error {
+	if a.Resolved {
+		fmt.Printf("[%s] RESOLVED: %s\n", a.Target, a.Message)
+	} else {
+		fmt.Printf("[%s] %s\n", a.Target, a.Message)
+	}
+	return nil
+}
+
+// WebhookSink POSTs the alert as JSON to a generic HTTP endpoint. The
+// body is the Alert itself (target, key, message, resolved, time); it
+// has no "text" field, so it is not in Slack's incoming-webhook format.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookSink builds a WebhookSink with a sane request timeout.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (w *WebhookSink) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("кодирование уведомления в JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("создание запроса вебхука: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("отправка вебхука: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("вебхук вернул статус %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPSink emails the alert through a plain SMTP relay.
+type SMTPSink struct {
+	Addr string
+	From string
+	To   []string
+	Auth smtp.Auth
+}
+
+// NewSMTPSink builds an SMTPSink for addr (host:port). It authenticates
+// with PLAIN auth when username is set, and without auth otherwise.
+func NewSMTPSink(addr, username, password, from string, to []string) *SMTPSink {
+	host := addr
+	if i := strings.IndexByte(addr, ':'); i >= 0 {
+		host = addr[:i]
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPSink{Addr: addr, From: from, To: to, Auth: auth}
+}
+
+func (s *SMTPSink) Notify(_ context.Context, a Alert) error {
+	subject := fmt.Sprintf("[%s] ALERT: %s", a.Target, a.Key)
+	if a.Resolved {
+		subject = fmt.Sprintf("[%s] RESOLVED: %s", a.Target, a.Key)
+	}
+
+	msg := fmt.Appendf(nil, "Subject: %s\r\n\r\n%s\r\n", subject, a.Message)
+
+	if err := smtp.SendMail(s.Addr, s.Auth, s.From, s.To, msg); err != nil {
+		return fmt.Errorf("отправка письма через SMTP: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package config loads the list of servers to poll from a YAML or JSON
+// file, replacing the single hardcoded statsURL with a fleet of targets
+// that can each override the global poll interval, timeout and alert
+// thresholds.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Thresholds holds the alert thresholds for a single target. A zero value
+// means "use the global default" - see Target.Thresholds.
+type Thresholds struct {
+	LoadAvg   float64 `yaml:"load_avg_threshold" json:"load_avg_threshold"`
+	MemUsage  float64 `yaml:"mem_usage_threshold" json:"mem_usage_threshold"`
+	DiskUsage float64 `yaml:"disk_usage_threshold" json:"disk_usage_threshold"`
+	NetUsage  float64 `yaml:"net_usage_threshold" json:"net_usage_threshold"`
+
+	// DiskFullETA fires an alert once the projected time until the disk
+	// fills up (based on the recent fill rate) drops below this
+	// duration. Zero disables the check.
+	DiskFullETA Duration `yaml:"disk_full_eta_threshold" json:"disk_full_eta_threshold"`
+}
+
+// Target describes a single server to poll.
+type Target struct {
+	Name         string     `yaml:"name" json:"name"`
+	URL          string     `yaml:"url" json:"url"`
+	PollInterval Duration   `yaml:"poll_interval" json:"poll_interval"`
+	Timeout      Duration   `yaml:"timeout" json:"timeout"`
+	Thresholds   Thresholds `yaml:"thresholds" json:"thresholds"`
+}
+
+// WebhookConfig configures the generic HTTP webhook alert sink.
+type WebhookConfig struct {
+	URL string `yaml:"url" json:"url"`
+}
+
+// SMTPConfig configures the email alert sink.
+type SMTPConfig struct {
+	Addr     string   `yaml:"addr" json:"addr"`
+	Username string   `yaml:"username" json:"username"`
+	Password string   `yaml:"password" json:"password"`
+	From     string   `yaml:"from" json:"from"`
+	To       []string `yaml:"to" json:"to"`
+}
+
+// AlertingConfig configures how alerts are routed to sinks.
+type AlertingConfig struct {
+	// Cooldown is the minimum time between repeated notifications for
+	// the same alert key while the condition stays true.
+	Cooldown Duration       `yaml:"cooldown" json:"cooldown"`
+	Webhook  *WebhookConfig `yaml:"webhook" json:"webhook"`
+	SMTP     *SMTPConfig    `yaml:"smtp" json:"smtp"`
+}
+
+// Config is the top-level shape of the config file.
+type Config struct {
+	Targets  []Target       `yaml:"targets" json:"targets"`
+	Alerting AlertingConfig `yaml:"alerting" json:"alerting"`
+
+	// HistoryCapacity is the number of samples retained per target for
+	// trend analysis (EWMA, disk-fill ETA) and the /history endpoint.
+	// Zero uses history.DefaultCapacity.
+	HistoryCapacity int `yaml:"history_capacity" json:"history_capacity"`
+}
+
+// Load reads and parses a config file. The format (YAML or JSON) is
+// chosen from the file extension: ".json" is parsed as JSON, anything
+// else (".yaml", ".yml", ...) is parsed as YAML.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("чтение конфигурационного файла: %w", err)
+	}
+
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("разбор JSON-конфигурации: %w", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("разбор YAML-конфигурации: %w", err)
+		}
+	}
+
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("конфигурация не содержит ни одной цели (targets)")
+	}
+
+	for i := range cfg.Targets {
+		if cfg.Targets[i].Name == "" {
+			cfg.Targets[i].Name = cfg.Targets[i].URL
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadDurationsYAMLAndJSON(t *testing.T) {
+	yamlBody := `
+targets:
+  - name: srv1
+    url: http://srv1/_stats
+    poll_interval: 10s
+    timeout: 5s
+    thresholds:
+      disk_full_eta_threshold: 24h
+alerting:
+  cooldown: 5m
+`
+	jsonBody := `{
+  "targets": [{
+    "name": "srv1",
+    "url": "http://srv1/_stats",
+    "poll_interval": "10s",
+    "timeout": "5s",
+    "thresholds": {"disk_full_eta_threshold": "24h"}
+  }],
+  "alerting": {"cooldown": "5m"}
+}`
+
+	for _, tc := range []struct {
+		name string
+		ext  string
+		body string
+	}{
+		{"yaml", ".yaml", yamlBody},
+		{"json", ".json", jsonBody},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "servers"+tc.ext)
+			if err := os.WriteFile(path, []byte(tc.body), 0o644); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load(%s) = %v", tc.ext, err)
+			}
+
+			target := cfg.Targets[0]
+			if time.Duration(target.PollInterval) != 10*time.Second {
+				t.Errorf("PollInterval = %s, want 10s", time.Duration(target.PollInterval))
+			}
+			if time.Duration(target.Timeout) != 5*time.Second {
+				t.Errorf("Timeout = %s, want 5s", time.Duration(target.Timeout))
+			}
+			if time.Duration(target.Thresholds.DiskFullETA) != 24*time.Hour {
+				t.Errorf("DiskFullETA = %s, want 24h", time.Duration(target.Thresholds.DiskFullETA))
+			}
+			if time.Duration(cfg.Alerting.Cooldown) != 5*time.Minute {
+				t.Errorf("Cooldown = %s, want 5m", time.Duration(cfg.Alerting.Cooldown))
+			}
+		})
+	}
+}
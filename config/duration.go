@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration wraps time.Duration so it decodes from a human-readable
+// string ("10s", "5m") from both YAML and JSON config files. A plain
+// number is still accepted and interpreted as nanoseconds, matching
+// time.Duration's own JSON encoding.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	// An integer scalar also decodes into a string, so try it first.
+	var ns int64
+	if err := value.Decode(&ns); err == nil {
+		*d = Duration(ns)
+		return nil
+	}
+
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return fmt.Errorf("разбор длительности: %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("разбор длительности %q: %w", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("разбор длительности %q: %w", s, err)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+
+	var ns int64
+	if err := json.Unmarshal(data, &ns); err != nil {
+		return fmt.Errorf("разбор длительности: %w", err)
+	}
+	*d = Duration(ns)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
@@ -0,0 +1,178 @@
+// Package history keeps a short in-memory window of recent samples per
+// target and derives trend signals from it - an EWMA of load average,
+// the disk-fill rate, and a projected time-until-full - so alerts can
+// warn before a threshold is actually crossed.
+package history
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCapacity holds 1h of samples at the default 10s poll interval.
+const DefaultCapacity = 360
+
+// Sample is one polled data point for a target.
+type Sample struct {
+	Time      time.Time
+	LoadAvg   float64
+	TotalMem  int64
+	UsedMem   int64
+	TotalDisk int64
+	UsedDisk  int64
+	TotalNet  int64
+	UsedNet   int64
+}
+
+// Buffer is a fixed-capacity ring buffer of Samples for one target.
+type Buffer struct {
+	mu       sync.Mutex
+	samples  []Sample
+	capacity int
+	next     int
+	full     bool
+}
+
+// NewBuffer creates a Buffer retaining at most capacity samples.
+func NewBuffer(capacity int) *Buffer {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Buffer{
+		samples:  make([]Sample, capacity),
+		capacity: capacity,
+	}
+}
+
+// Add records a new sample, evicting the oldest one once the buffer is
+// at capacity.
+func (b *Buffer) Add(s Sample) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.samples[b.next] = s
+	b.next = (b.next + 1) % b.capacity
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Samples returns a copy of the retained samples in chronological order.
+func (b *Buffer) Samples() []Sample {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		out := make([]Sample, b.next)
+		copy(out, b.samples[:b.next])
+		return out
+	}
+
+	out := make([]Sample, b.capacity)
+	copy(out, b.samples[b.next:])
+	copy(out[b.capacity-b.next:], b.samples[:b.next])
+	return out
+}
+
+// EWMALoadAvg returns the exponentially weighted moving average of the
+// load average over the retained samples, with smoothing factor alpha
+// (0 < alpha <= 1; larger alpha weights recent samples more heavily).
+// The second return value is false if there are no samples yet.
+func (b *Buffer) EWMALoadAvg(alpha float64) (float64, bool) {
+	samples := b.Samples()
+	if len(samples) == 0 {
+		return 0, false
+	}
+
+	series := EWMALoadAvgSeries(samples, alpha)
+	return series[len(series)-1], true
+}
+
+// EWMALoadAvgSeries computes the running EWMA of LoadAvg at every
+// sample, in chronological order, so callers that need the trend rather
+// than just its latest value (e.g. the /history endpoint) don't have to
+// recompute it sample-by-sample themselves.
+func EWMALoadAvgSeries(samples []Sample, alpha float64) []float64 {
+	out := make([]float64, len(samples))
+	if len(samples) == 0 {
+		return out
+	}
+
+	ewma := samples[0].LoadAvg
+	out[0] = ewma
+	for i := 1; i < len(samples); i++ {
+		ewma = alpha*samples[i].LoadAvg + (1-alpha)*ewma
+		out[i] = ewma
+	}
+	return out
+}
+
+// DiskFillRate returns the rate of change of used disk space, in
+// bytes/sec, estimated by linear regression over the retained samples.
+// A positive rate means the disk is filling up. ok is false if there
+// are fewer than two samples.
+func (b *Buffer) DiskFillRate() (rate float64, ok bool) {
+	samples := b.Samples()
+	if len(samples) < 2 {
+		return 0, false
+	}
+
+	t0 := samples[0].Time
+	xs := make([]float64, len(samples))
+	ys := make([]float64, len(samples))
+	for i, s := range samples {
+		xs[i] = s.Time.Sub(t0).Seconds()
+		ys[i] = float64(s.UsedDisk)
+	}
+
+	slope, _, ok := linearRegression(xs, ys)
+	return slope, ok
+}
+
+// DiskFullETA projects how long until the disk fills up, given its
+// current fill rate. ok is false if the disk is not currently filling
+// up (rate <= 0) or there isn't enough history to estimate a rate.
+func (b *Buffer) DiskFullETA() (eta time.Duration, ok bool) {
+	rate, ok := b.DiskFillRate()
+	if !ok || rate <= 0 {
+		return 0, false
+	}
+
+	samples := b.Samples()
+	last := samples[len(samples)-1]
+	if last.TotalDisk <= 0 {
+		return 0, false
+	}
+
+	free := float64(last.TotalDisk - last.UsedDisk)
+	if free <= 0 {
+		return 0, true
+	}
+
+	return time.Duration(free / rate * float64(time.Second)), true
+}
+
+// linearRegression fits y = slope*x + intercept by least squares.
+func linearRegression(xs, ys []float64) (slope, intercept float64, ok bool) {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0, 0, false
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, 0, false
+	}
+
+	slope = (n*sumXY - sumX*sumY) / denom
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept, true
+}
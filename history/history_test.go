@@ -0,0 +1,82 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+const GiByte = 1 << 30
+
+func TestDiskFullETA(t *testing.T) {
+	b := NewBuffer(10)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Disk fills at 1 GiB/hour, starting at 90 GiB used out of 100 GiB.
+	for i := 0; i < 5; i++ {
+		b.Add(Sample{
+			Time:      start.Add(time.Duration(i) * time.Hour),
+			TotalDisk: 100 * GiByte,
+			UsedDisk:  (90 + int64(i)) * GiByte,
+		})
+	}
+
+	eta, ok := b.DiskFullETA()
+	if !ok {
+		t.Fatalf("DiskFullETA() ok = false, want true")
+	}
+
+	// 6 GiB free at the last sample, filling at ~1 GiB/hour -> ~6h left.
+	if eta < 5*time.Hour || eta > 7*time.Hour {
+		t.Errorf("DiskFullETA() = %s, want ~6h", eta)
+	}
+}
+
+func TestDiskFullETANotFilling(t *testing.T) {
+	b := NewBuffer(10)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		b.Add(Sample{
+			Time:      start.Add(time.Duration(i) * time.Hour),
+			TotalDisk: 100 * GiByte,
+			UsedDisk:  50 * GiByte,
+		})
+	}
+
+	if _, ok := b.DiskFullETA(); ok {
+		t.Errorf("DiskFullETA() ok = true for a flat disk usage, want false")
+	}
+}
+
+func TestEWMALoadAvgSeries(t *testing.T) {
+	samples := []Sample{{LoadAvg: 10}, {LoadAvg: 20}, {LoadAvg: 20}}
+
+	series := EWMALoadAvgSeries(samples, 0.5)
+	if len(series) != len(samples) {
+		t.Fatalf("len(series) = %d, want %d", len(series), len(samples))
+	}
+	if series[0] != 10 {
+		t.Errorf("series[0] = %v, want 10 (first sample, no smoothing yet)", series[0])
+	}
+	if series[1] != 15 {
+		t.Errorf("series[1] = %v, want 15", series[1])
+	}
+	if series[2] != 17.5 {
+		t.Errorf("series[2] = %v, want 17.5", series[2])
+	}
+}
+
+func TestEWMALoadAvg(t *testing.T) {
+	b := NewBuffer(10)
+	for _, load := range []float64{10, 20, 20} {
+		b.Add(Sample{LoadAvg: load})
+	}
+
+	got, ok := b.EWMALoadAvg(0.5)
+	if !ok {
+		t.Fatalf("EWMALoadAvg() ok = false, want true")
+	}
+	if got != 17.5 {
+		t.Errorf("EWMALoadAvg() = %v, want 17.5", got)
+	}
+}
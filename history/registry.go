@@ -0,0 +1,125 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Registry owns one Buffer per target, created lazily on first use, and
+// serves them over HTTP for external plotting.
+type Registry struct {
+	capacity int
+
+	mu      sync.Mutex
+	buffers map[string]*Buffer
+}
+
+// NewRegistry creates a Registry whose buffers retain at most capacity
+// samples each.
+func NewRegistry(capacity int) *Registry {
+	return &Registry{
+		capacity: capacity,
+		buffers:  make(map[string]*Buffer),
+	}
+}
+
+// Target returns the Buffer for the named target, creating it on first
+// use.
+func (r *Registry) Target(name string) *Buffer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buffers[name]
+	if !ok {
+		b = NewBuffer(r.capacity)
+		r.buffers[name] = b
+	}
+	return b
+}
+
+// point is one entry of a /history JSON response.
+type point struct {
+	Time  string  `json:"time"`
+	Value float64 `json:"value"`
+}
+
+// DefaultEWMAAlpha is the smoothing factor used for the ewma_load_avg
+// series served over /history.
+const DefaultEWMAAlpha = 0.3
+
+// series extracts a single metric from the retained samples, by name.
+func series(samples []Sample, metric string) ([]point, error) {
+	if metric == "ewma_load_avg" {
+		ewma := EWMALoadAvgSeries(samples, DefaultEWMAAlpha)
+		out := make([]point, len(samples))
+		for i, s := range samples {
+			out[i] = point{Time: s.Time.Format(timeLayout), Value: ewma[i]}
+		}
+		return out, nil
+	}
+
+	extract, err := metricExtractor(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]point, len(samples))
+	for i, s := range samples {
+		out[i] = point{Time: s.Time.Format(timeLayout), Value: extract(s)}
+	}
+	return out, nil
+}
+
+const timeLayout = "2006-01-02T15:04:05.999999999Z07:00"
+
+func metricExtractor(metric string) (func(Sample) float64, error) {
+	switch metric {
+	case "load_avg":
+		return func(s Sample) float64 { return s.LoadAvg }, nil
+	case "mem_usage_ratio":
+		return func(s Sample) float64 {
+			if s.TotalMem == 0 {
+				return 0
+			}
+			return float64(s.UsedMem) / float64(s.TotalMem)
+		}, nil
+	case "disk_free_bytes":
+		return func(s Sample) float64 { return float64(s.TotalDisk - s.UsedDisk) }, nil
+	case "net_free_bps":
+		return func(s Sample) float64 { return float64(s.TotalNet-s.UsedNet) * 8 }, nil
+	default:
+		return nil, fmt.Errorf("неизвестная метрика: %q", metric)
+	}
+}
+
+// Handler serves GET /history?target=...&metric=..., returning the
+// retained samples for that target/metric pair as a JSON time series.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		target := req.URL.Query().Get("target")
+		metric := req.URL.Query().Get("metric")
+		if target == "" || metric == "" {
+			http.Error(w, "требуются параметры target и metric", http.StatusBadRequest)
+			return
+		}
+
+		r.mu.Lock()
+		b, ok := r.buffers[target]
+		r.mu.Unlock()
+		if !ok {
+			http.Error(w, fmt.Sprintf("нет данных по цели %q", target), http.StatusNotFound)
+			return
+		}
+
+		points, err := series(b.Samples(), metric)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(points)
+	})
+}
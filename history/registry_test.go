@@ -0,0 +1,35 @@
+package history
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerEWMALoadAvg(t *testing.T) {
+	reg := NewRegistry(10)
+	buf := reg.Target("srv1")
+	for _, load := range []float64{10, 20, 20} {
+		buf.Add(Sample{LoadAvg: load})
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/history?target=srv1&metric=ewma_load_avg", nil)
+	reg.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var points []point
+	if err := json.Unmarshal(rec.Body.Bytes(), &points); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("len(points) = %d, want 3", len(points))
+	}
+	want := EWMALoadAvgSeries([]Sample{{LoadAvg: 10}, {LoadAvg: 20}, {LoadAvg: 20}}, DefaultEWMAAlpha)
+	if points[2].Value != want[2] {
+		t.Errorf("points[2].Value = %v, want %v", points[2].Value, want[2])
+	}
+}
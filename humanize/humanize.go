@@ -0,0 +1,63 @@
+// Package humanize formats byte counts and bitrates the way an operator
+// reading an alert expects to see them, instead of hardcoding "Mb" and
+// truncating to an integer.
+package humanize
+
+import "fmt"
+
+// IEC byte unit thresholds.
+const (
+	KiByte = 1 << 10
+	MiByte = 1 << 20
+	GiByte = 1 << 30
+	TiByte = 1 << 40
+)
+
+// IBytes formats a byte count using IEC binary units (KiB, MiB, GiB,
+// TiB), e.g. IBytes(1503238553) == "1.4 GiB".
+func IBytes(b int64) string {
+	abs := b
+	if abs < 0 {
+		abs = -abs
+	}
+
+	switch {
+	case abs >= TiByte:
+		return fmt.Sprintf("%.1f TiB", float64(b)/TiByte)
+	case abs >= GiByte:
+		return fmt.Sprintf("%.1f GiB", float64(b)/GiByte)
+	case abs >= MiByte:
+		return fmt.Sprintf("%.1f MiB", float64(b)/MiByte)
+	case abs >= KiByte:
+		return fmt.Sprintf("%.1f KiB", float64(b)/KiByte)
+	default:
+		return fmt.Sprintf("%d B", b)
+	}
+}
+
+// SI bitrate unit thresholds.
+const (
+	Kbit = 1000
+	Mbit = 1000 * Kbit
+	Gbit = 1000 * Mbit
+)
+
+// Bitrate formats a rate given in bits per second using SI units
+// (Kbit/s, Mbit/s, Gbit/s), selected by magnitude.
+func Bitrate(bps float64) string {
+	abs := bps
+	if abs < 0 {
+		abs = -abs
+	}
+
+	switch {
+	case abs >= Gbit:
+		return fmt.Sprintf("%.2f Gbit/s", bps/Gbit)
+	case abs >= Mbit:
+		return fmt.Sprintf("%.2f Mbit/s", bps/Mbit)
+	case abs >= Kbit:
+		return fmt.Sprintf("%.2f Kbit/s", bps/Kbit)
+	default:
+		return fmt.Sprintf("%.0f bit/s", bps)
+	}
+}
@@ -0,0 +1,39 @@
+package humanize
+
+import "testing"
+
+func TestIBytes(t *testing.T) {
+	cases := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1536, "1.5 KiB"},
+		{13 * GiByte, "13.0 GiB"},
+		{1503238553, "1.4 GiB"},
+	}
+
+	for _, c := range cases {
+		if got := IBytes(c.in); got != c.want {
+			t.Errorf("IBytes(%d) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestBitrate(t *testing.T) {
+	cases := []struct {
+		in   float64
+		want string
+	}{
+		{500, "500 bit/s"},
+		{1_500_000, "1.50 Mbit/s"},
+		{2_500_000_000, "2.50 Gbit/s"},
+	}
+
+	for _, c := range cases {
+		if got := Bitrate(c.in); got != c.want {
+			t.Errorf("Bitrate(%v) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
@@ -1,27 +1,40 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
+
+	"github.com/iambooldog/go-lesson1/alert"
+	"github.com/iambooldog/go-lesson1/config"
+	"github.com/iambooldog/go-lesson1/history"
+	"github.com/iambooldog/go-lesson1/humanize"
+	"github.com/iambooldog/go-lesson1/metrics"
 )
 
 const (
-	statsURL = "http://srv.msk01.gigacorp.local/_stats"
+	defaultMetricsAddr = ":9100"
 
-	pollInterval = 10 * time.Second
+	defaultPollInterval = 10 * time.Second
+	defaultTimeout      = 5 * time.Second
+	defaultCooldown     = 5 * time.Minute
 
 	maxConsecutiveErrors = 3
 
-	loadAvgThreshold   = 30.0
-	memUsageThreshold  = 0.80
-	diskUsageThreshold = 0.90
-	netUsageThreshold  = 0.90
+	defaultLoadAvgThreshold   = 30.0
+	defaultMemUsageThreshold  = 0.80
+	defaultDiskUsageThreshold = 0.90
+	defaultNetUsageThreshold  = 0.90
 )
 
 type ServerStats struct {
@@ -35,29 +48,123 @@ type ServerStats struct {
 }
 
 func main() {
-	consecutiveErrors := 0
+	configPath := flag.String("config", "servers.yaml", "путь к конфигурационному файлу целей (YAML или JSON)")
+	metricsAddr := flag.String("metrics-addr", defaultMetricsAddr, "адрес для /metrics и /history (например :9100)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("не удалось загрузить конфигурацию: %v", err)
+	}
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	histReg := history.NewRegistry(cfg.HistoryCapacity)
+	go serveHTTP(*metricsAddr, histReg)
+
+	router := newAlertRouter(cfg.Alerting)
+
+	var wg sync.WaitGroup
+	for _, target := range cfg.Targets {
+		target := target
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pollTarget(ctx, target, router, histReg)
+		}()
 	}
 
-	log.Printf("Запуск мониторинга сервера: %s (интервал: %s)\n", statsURL, pollInterval)
+	wg.Wait()
+	log.Println("Мониторинг остановлен.")
+}
+
+// newAlertRouter builds the alert.Router for the process from the
+// configured sinks. A StdoutSink is always included.
+func newAlertRouter(cfg config.AlertingConfig) *alert.Router {
+	sinks := []alert.Sink{alert.StdoutSink{}}
+
+	if cfg.Webhook != nil && cfg.Webhook.URL != "" {
+		sinks = append(sinks, alert.NewWebhookSink(cfg.Webhook.URL))
+	}
+	if cfg.SMTP != nil && cfg.SMTP.Addr != "" {
+		sinks = append(sinks, alert.NewSMTPSink(cfg.SMTP.Addr, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From, cfg.SMTP.To))
+	}
+
+	cooldown := time.Duration(cfg.Cooldown)
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+
+	return alert.NewRouter(cooldown, sinks...)
+}
+
+// pollTarget repeatedly fetches and checks stats for a single target
+// until ctx is cancelled (SIGINT/SIGTERM).
+func pollTarget(ctx context.Context, target config.Target, router *alert.Router, histReg *history.Registry) {
+	interval := time.Duration(target.PollInterval)
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	timeout := time.Duration(target.Timeout)
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	client := &http.Client{Timeout: timeout}
+
+	log.Printf("Запуск мониторинга сервера %q: %s (интервал: %s)\n", target.Name, target.URL, interval)
+
+	consecutiveErrors := 0
+	buf := histReg.Target(target.Name)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
 	for {
-		stats, err := fetchAndParseStats(client, statsURL)
+		stats, err := fetchAndParseStats(client, target.URL)
 
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Ошибка (попытка %d): %v\n", consecutiveErrors+1, err)
+			fmt.Fprintf(os.Stderr, "[%s] Ошибка (попытка %d): %v\n", target.Name, consecutiveErrors+1, err)
 			consecutiveErrors++
 			if consecutiveErrors >= maxConsecutiveErrors {
-				fmt.Println("Unable to fetch server statistic.")
+				fmt.Printf("[%s] Unable to fetch server statistic.\n", target.Name)
 			}
+			metrics.IncScrapeErrors(target.Name)
 		} else {
 			consecutiveErrors = 0
-			checkMetrics(stats)
+			buf.Add(history.Sample{
+				Time:      time.Now(),
+				LoadAvg:   stats.LoadAvg,
+				TotalMem:  stats.TotalMem,
+				UsedMem:   stats.UsedMem,
+				TotalDisk: stats.TotalDisk,
+				UsedDisk:  stats.UsedDisk,
+				TotalNet:  stats.TotalNet,
+				UsedNet:   stats.UsedNet,
+			})
+			checkMetrics(ctx, router, &target, stats, buf)
+			metrics.Update(target.Name, metrics.Stats(*stats))
 		}
 
-		time.Sleep(pollInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serveHTTP starts the Prometheus /metrics endpoint and the /history
+// time-series endpoint. It runs for the lifetime of the process, so a
+// failure to bind is fatal.
+func serveHTTP(addr string, histReg *history.Registry) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/history", histReg.Handler())
+
+	log.Printf("Экспорт метрик Prometheus и истории на %s (/metrics, /history)\n", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("не удалось запустить HTTP-сервер: %v", err)
 	}
 }
 
@@ -118,33 +225,59 @@ func fetchAndParseStats(client *http.Client, url string) (*ServerStats, error) {
 	return stats, nil
 }
 
-func checkMetrics(stats *ServerStats) {
-	if stats.LoadAvg > loadAvgThreshold {
-		fmt.Printf("Load Average is too high: %g\n", stats.LoadAvg)
+func checkMetrics(ctx context.Context, router *alert.Router, target *config.Target, stats *ServerStats, buf *history.Buffer) {
+	loadAvgThreshold := defaultLoadAvgThreshold
+	if target.Thresholds.LoadAvg > 0 {
+		loadAvgThreshold = target.Thresholds.LoadAvg
+	}
+	memUsageThreshold := defaultMemUsageThreshold
+	if target.Thresholds.MemUsage > 0 {
+		memUsageThreshold = target.Thresholds.MemUsage
+	}
+	diskUsageThreshold := defaultDiskUsageThreshold
+	if target.Thresholds.DiskUsage > 0 {
+		diskUsageThreshold = target.Thresholds.DiskUsage
 	}
+	netUsageThreshold := defaultNetUsageThreshold
+	if target.Thresholds.NetUsage > 0 {
+		netUsageThreshold = target.Thresholds.NetUsage
+	}
+
+	router.Fire(ctx, target.Name, "load_avg",
+		fmt.Sprintf("Load Average is too high: %g", stats.LoadAvg),
+		stats.LoadAvg > loadAvgThreshold)
 
 	if stats.TotalMem > 0 {
 		memUsage := float64(stats.UsedMem) / float64(stats.TotalMem)
-		if memUsage > memUsageThreshold {
-			fmt.Printf("Memory usage too high: %.0f%%\n", memUsage*100)
-		}
+		router.Fire(ctx, target.Name, "mem_usage",
+			fmt.Sprintf("Memory usage too high: %.0f%% (%s / %s)", memUsage*100,
+				humanize.IBytes(stats.UsedMem), humanize.IBytes(stats.TotalMem)),
+			memUsage > memUsageThreshold)
 	}
 
 	if stats.TotalDisk > 0 {
 		diskUsage := float64(stats.UsedDisk) / float64(stats.TotalDisk)
-		if diskUsage > diskUsageThreshold {
-			freeDiskBytes := stats.TotalDisk - stats.UsedDisk
-			freeDiskMb := freeDiskBytes / 1048576
-			fmt.Printf("Free disk space is too low: %d Mb left\n", freeDiskMb)
-		}
+		freeDiskBytes := stats.TotalDisk - stats.UsedDisk
+		router.Fire(ctx, target.Name, "disk_usage",
+			fmt.Sprintf("Free disk space is too low: %s left", humanize.IBytes(freeDiskBytes)),
+			diskUsage > diskUsageThreshold)
 	}
 
 	if stats.TotalNet > 0 {
 		netUsage := float64(stats.UsedNet) / float64(stats.TotalNet)
-		if netUsage > netUsageThreshold {
-			freeNetBps := stats.TotalNet - stats.UsedNet
-			freeNetMbps := float64(freeNetBps*8) / 1_000_000.0
-			fmt.Printf("Network bandwidth usage high: %.2f Mbit/s available\n", freeNetMbps)
+		freeNetBps := float64(stats.TotalNet-stats.UsedNet) * 8
+		router.Fire(ctx, target.Name, "net_usage",
+			fmt.Sprintf("Network bandwidth usage high: %s available", humanize.Bitrate(freeNetBps)),
+			netUsage > netUsageThreshold)
+	}
+
+	if target.Thresholds.DiskFullETA > 0 {
+		eta, ok := buf.DiskFullETA()
+		message := "Disk fill rate has stabilized; no longer projected to run out"
+		if ok {
+			message = fmt.Sprintf("Disk projected to fill up in %s", eta.Round(time.Minute))
 		}
+		router.Fire(ctx, target.Name, "disk_full_eta", message,
+			ok && eta < time.Duration(target.Thresholds.DiskFullETA))
 	}
 }
@@ -0,0 +1,81 @@
+// Package metrics exposes the poller's server statistics as Prometheus
+// gauges so the tool can be scraped by an existing Prometheus/Grafana
+// stack instead of (or in addition to) printing alerts to stdout. Every
+// metric is labeled by target so a single exporter can cover a fleet of
+// polled servers.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	loadAverage = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "server_load_average",
+		Help: "Current load average reported by the polled server.",
+	}, []string{"target"})
+
+	memoryUsageRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "server_memory_usage_ratio",
+		Help: "Fraction of total memory currently in use (0-1).",
+	}, []string{"target"})
+
+	diskFreeBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "server_disk_free_bytes",
+		Help: "Free disk space in bytes.",
+	}, []string{"target"})
+
+	netFreeBps = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "server_net_free_bps",
+		Help: "Free network bandwidth in bits per second.",
+	}, []string{"target"})
+
+	scrapeErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "server_scrape_errors_total",
+		Help: "Number of failed attempts to fetch and parse server stats.",
+	}, []string{"target"})
+)
+
+// Stats is the subset of server statistics needed to update the gauges.
+// It mirrors main.ServerStats without importing package main.
+type Stats struct {
+	LoadAvg   float64
+	TotalMem  int64
+	UsedMem   int64
+	TotalDisk int64
+	UsedDisk  int64
+	TotalNet  int64
+	UsedNet   int64
+}
+
+// Update refreshes the gauges for target from a freshly fetched sample.
+func Update(target string, stats Stats) {
+	loadAverage.WithLabelValues(target).Set(stats.LoadAvg)
+
+	if stats.TotalMem > 0 {
+		memoryUsageRatio.WithLabelValues(target).Set(float64(stats.UsedMem) / float64(stats.TotalMem))
+	}
+
+	if stats.TotalDisk > 0 {
+		diskFreeBytes.WithLabelValues(target).Set(float64(stats.TotalDisk - stats.UsedDisk))
+	}
+
+	if stats.TotalNet > 0 {
+		netFreeBps.WithLabelValues(target).Set(float64(stats.TotalNet-stats.UsedNet) * 8)
+	}
+}
+
+// IncScrapeErrors increments the scrape error counter for target. Call it
+// whenever fetchAndParseStats fails.
+func IncScrapeErrors(target string) {
+	scrapeErrorsTotal.WithLabelValues(target).Inc()
+}
+
+// Handler returns the HTTP handler to mount at /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}